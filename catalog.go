@@ -0,0 +1,127 @@
+package chunk
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// A ChunkEntry records the position and identity of a single chunk found by
+// Index, without needing to hold that chunk open.
+type ChunkEntry struct {
+	// ID is the chunk's 4-byte name, as returned by Chunk.Name.
+	ID string
+	// Offset is the absolute offset of the start of the chunk (its ID
+	// field) within the io.ReadSeeker passed to Index.
+	Offset int64
+	// Size is the chunk's size, as returned by Chunk.Size.
+	Size uint32
+	// Path is the slash-separated chain of IDs from the root of the file
+	// down to this chunk, such as "FORM/LIST/IN2 ". It is suitable for
+	// passing to Catalog.Open.
+	Path string
+}
+
+// Index scans f from its current position to the end, recording a
+// ChunkEntry for every chunk it finds, recursing into group chunks (FORM,
+// LIST, CAT , RIFF, RIFX) to record their children too. It leaves f
+// positioned at EOF. The size fields are read as big-endian; use
+// IndexWithOrder to index RIFF/RIFX (and therefore WAV/AVI) files, whose
+// size fields are little-endian.
+func Index(f io.ReadSeeker) ([]ChunkEntry, error) {
+	return IndexWithOrder(f, binary.BigEndian)
+}
+
+// IndexWithOrder is like Index, but reads every chunk's size field using the
+// given byte order.
+func IndexWithOrder(f io.ReadSeeker, order binary.ByteOrder) ([]ChunkEntry, error) {
+	var entries []ChunkEntry
+	if err := indexChunks(f, f, "", order, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func indexChunks(root io.ReadSeeker, r io.ReadSeeker, prefix string, order binary.ByteOrder, entries *[]ChunkEntry) error {
+	for {
+		offset, err := root.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return err
+		}
+		chunk, err := NewWithOrder(r, order)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		path := chunk.Name()
+		if prefix != "" {
+			path = prefix + "/" + path
+		}
+		*entries = append(*entries, ChunkEntry{ID: chunk.Name(), Offset: offset, Size: chunk.Size(), Path: path})
+		if chunk.Kind() == "group" {
+			if err := indexChunks(root, chunk, path, order, entries); err != nil {
+				return err
+			}
+		}
+		chunk.Skip()
+	}
+}
+
+// A Catalog is a random-access index of the chunks in an IFF file, built by
+// scanning it once with Index. It lets a caller jump straight to a known
+// chunk instead of linearly walking and Skip-ing through the file.
+type Catalog struct {
+	f       io.ReadSeeker
+	order   binary.ByteOrder
+	entries []ChunkEntry
+	byPath  map[string]ChunkEntry
+}
+
+// NewCatalog scans f with Index and returns a Catalog over the result,
+// restoring f's original position once scanning is complete. Use
+// NewCatalogWithOrder for RIFF/RIFX (and therefore WAV/AVI) files, whose
+// size fields are little-endian.
+func NewCatalog(f io.ReadSeeker) (*Catalog, error) {
+	return NewCatalogWithOrder(f, binary.BigEndian)
+}
+
+// NewCatalogWithOrder is like NewCatalog, but scans f with IndexWithOrder
+// and reopens chunks using the given byte order.
+func NewCatalogWithOrder(f io.ReadSeeker, order binary.ByteOrder) (*Catalog, error) {
+	start, err := f.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := IndexWithOrder(f, order)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := f.Seek(start, io.SeekStart); err != nil {
+		return nil, err
+	}
+	byPath := make(map[string]ChunkEntry, len(entries))
+	for _, entry := range entries {
+		byPath[entry.Path] = entry
+	}
+	return &Catalog{f: f, order: order, entries: entries, byPath: byPath}, nil
+}
+
+// Entries returns every ChunkEntry found when the Catalog was built.
+func (this *Catalog) Entries() []ChunkEntry {
+	return this.entries
+}
+
+// Open seeks directly to the chunk named by path, such as
+// "FORM/LIST/IN2 ", and returns it as a Chunk ready to be read or skipped.
+func (this *Catalog) Open(path string) (*Chunk, error) {
+	entry, ok := this.byPath[path]
+	if !ok {
+		return nil, fmt.Errorf("chunk: no such chunk %q in catalog", path)
+	}
+	if _, err := this.f.Seek(entry.Offset, io.SeekStart); err != nil {
+		return nil, err
+	}
+	return NewWithOrder(this.f, this.order)
+}
@@ -2,22 +2,45 @@
 // The API is based on the Python standard library's "chunk" module.
 package chunk
 
+import "encoding/binary"
+import "fmt"
 import "io"
-import "errors"
 
 // A Chunk represents a single chunk in an IFF file.
 // Chunks implement the io.Reader and io.Seeker interfaces.
 type Chunk struct {
-	file   io.ReadSeeker
-	id     string
-	size   uint32
-	base   uint32
-	offset uint32
+	file     io.ReadSeeker
+	id       string
+	size     uint32
+	base     uint32
+	offset   uint32
+	order    binary.ByteOrder
+	formType string
 }
 
-// Returns a new chunk. An instance of Chunk is specifically allowed as the
-// argument to New. This is used to read chunks inside other chunks.
+// groupKinds are the chunk IDs that introduce a 4-byte form-type field
+// followed by nested child chunks, rather than raw payload data.
+var groupKinds = map[string]bool{
+	"FORM": true,
+	"LIST": true,
+	"CAT ": true,
+	"RIFF": true,
+	"RIFX": true,
+}
+
+// Returns a new chunk, reading its size as a big-endian integer. An instance
+// of Chunk is specifically allowed as the argument to New. This is used to
+// read chunks inside other chunks. Use NewWithOrder to read formats such as
+// RIFF that store the size as little-endian.
 func New(f io.ReadSeeker) (*Chunk, error) {
+	return NewWithOrder(f, binary.BigEndian)
+}
+
+// NewWithOrder is like New, but reads the chunk's size field using the given
+// byte order. Use binary.LittleEndian for RIFF/RIFX (and therefore WAV/AVI)
+// files; plain IFF files such as AIFF and ILBM use binary.BigEndian, as New
+// does by default.
+func NewWithOrder(f io.ReadSeeker, order binary.ByteOrder) (*Chunk, error) {
 	id := make([]byte, 4)
 	if n, err := f.Read(id); n != 4 || err != nil {
 		return nil, err
@@ -26,9 +49,22 @@ func New(f io.ReadSeeker) (*Chunk, error) {
 	if n, err := f.Read(sizeBits); n != 4 || err != nil {
 		return nil, err
 	}
-	size := (uint32(sizeBits[0]) << 24) | (uint32(sizeBits[1]) << 16) | (uint32(sizeBits[2]) << 8) | uint32(sizeBits[3])
+	size := order.Uint32(sizeBits)
 	base, _ := f.Seek(0, 1)
-	return &Chunk{f, string(id), size, uint32(base), 0}, nil
+	chunk := &Chunk{file: f, id: string(id), size: size, base: uint32(base), offset: 0, order: order}
+	if groupKinds[chunk.id] {
+		formType := make([]byte, 4)
+		if n, err := chunk.Read(formType); n != 4 || err != nil {
+			return nil, err
+		}
+		chunk.formType = string(formType)
+	}
+	return chunk, nil
+}
+
+// Make is an alias for New.
+func Make(f io.ReadSeeker) (*Chunk, error) {
+	return New(f)
 }
 
 // Returns the name (ID) of the chunk. This is the first 4 bytes of the chunk.
@@ -41,20 +77,72 @@ func (this *Chunk) Size() (size uint32) {
 	return this.size
 }
 
-// Seek implements the io.Seeker interface.
+// Kind returns "group" for container chunks (FORM, LIST, CAT , RIFF, RIFX)
+// that carry a form-type and nested child chunks, and "data" for ordinary
+// chunks.
+func (this *Chunk) Kind() string {
+	if groupKinds[this.id] {
+		return "group"
+	}
+	return "data"
+}
+
+// FormType returns the 4-byte form type of a group chunk, such as "AIFF" or
+// "WAVE". It returns the empty string for chunks where Kind() is "data".
+func (this *Chunk) FormType() string {
+	return this.formType
+}
+
+// Iter returns a function that walks the child chunks of a group chunk one
+// at a time, skipping each chunk (and its padding byte, if any) before
+// reading the next. It returns io.EOF once there are no more children.
+func (this *Chunk) Iter() func() (*Chunk, error) {
+	var last *Chunk
+	return func() (*Chunk, error) {
+		if last != nil {
+			last.Skip()
+		}
+		if this.offset >= this.size {
+			return nil, io.EOF
+		}
+		child, err := NewWithOrder(this, this.order)
+		if err != nil {
+			return nil, err
+		}
+		last = child
+		return child, nil
+	}
+}
+
+// A SeekError records an attempt to seek a Chunk to an offset outside
+// [0, Size()].
+type SeekError struct {
+	// Offset is the absolute offset, relative to the start of the chunk,
+	// that was requested.
+	Offset int64
+}
+
+func (this *SeekError) Error() string {
+	return fmt.Sprintf("chunk: invalid seek to offset %d", this.Offset)
+}
+
+// Seek implements the io.Seeker interface. Seeking to Size() is allowed, as
+// required by io.Seeker; a subsequent Read will simply return io.EOF. On
+// failure, the chunk's offset is left unchanged and returned. An offset
+// outside [0, Size()] yields a *SeekError describing the attempted offset;
+// a failed seek of the underlying reader yields that reader's error.
 func (this *Chunk) Seek(offset int64, whence int) (ret int64, err error) {
 	switch whence {
-	case 1:
+	case io.SeekCurrent:
 		offset += int64(this.offset)
-	case 2:
+	case io.SeekEnd:
 		offset += int64(this.size)
 	}
 	if offset < 0 || offset > int64(this.size) {
-		return int64(this.offset), errors.New("Invalid seek offset")
+		return int64(this.offset), &SeekError{offset}
 	}
-	pos, err := this.file.Seek(int64(this.base)+offset, 0)
-	if err != nil {
-		return int64(pos) - int64(this.base), err
+	if _, err := this.file.Seek(int64(this.base)+offset, io.SeekStart); err != nil {
+		return int64(this.offset), err
 	}
 	this.offset = uint32(offset)
 	return offset, nil
@@ -70,7 +158,7 @@ func (this *Chunk) Skip() {
 		pos++
 	}
 	this.offset = this.size
-	this.file.Seek(int64(pos), 0)
+	this.file.Seek(int64(pos), io.SeekStart)
 }
 
 // Read implements the io.Reader interface.
@@ -86,11 +174,10 @@ func (this *Chunk) Read(buffer []byte) (n int, err error) {
 	n, err = this.file.Read(buffer)
 	this.offset += uint32(n)
 
-	// Check if we need to move up one more.
+	// Skip the pad byte, if any, without counting it towards offset: the
+	// chunk's logical offset never exceeds its size.
 	if this.offset == this.size && (this.size&1) == 1 {
-		if _, err := this.file.Seek(1, 1); err == nil {
-			this.offset++
-		}
+		this.file.Seek(1, io.SeekCurrent)
 	}
 
 	return
@@ -2,7 +2,9 @@ package chunk
 
 import (
 	"bytes"
+	"encoding/binary"
 	"io"
+	"os"
 	"testing"
 )
 
@@ -145,6 +147,55 @@ func TestSeek(t *testing.T) {
 	checkByte(chunkFoo, 129, t)
 }
 
+func TestSeekToEnd(t *testing.T) {
+	f := bytes.NewReader(testString)
+	chunkTest, _ := Make(f)
+	chunkTest.Skip()
+	chunkFoo, _ := Make(f)
+
+	// io.Seeker permits seeking exactly to EOF.
+	if pos, err := chunkFoo.Seek(0, io.SeekEnd); pos != 20 || err != nil {
+		t.Errorf("Failed to seek to end (at %d): %s", pos, err)
+	}
+
+	// A further read should report io.EOF, not an error.
+	buffer := make([]byte, 1)
+	if n, err := chunkFoo.Read(buffer); n != 0 || err != io.EOF {
+		t.Errorf("Expected io.EOF reading past end, got %d bytes (%s)", n, err)
+	}
+
+	// Seeking past the end should fail with a *SeekError naming the
+	// attempted offset, and leave the position unchanged.
+	_, err := chunkFoo.Seek(1, io.SeekCurrent)
+	seekErr, ok := err.(*SeekError)
+	if !ok {
+		t.Fatalf("Expected *SeekError, got %T (%s)", err, err)
+	}
+	if seekErr.Offset != 21 {
+		t.Errorf("SeekError.Offset %d != 21", seekErr.Offset)
+	}
+	if pos, _ := chunkFoo.Seek(0, io.SeekCurrent); pos != 20 {
+		t.Errorf("Position changed after failed seek: %d != 20", pos)
+	}
+}
+
+// Reading to the end of an odd-sized chunk must not push the logical
+// offset past Size(), or a subsequent Seek(0, io.SeekCurrent) would fail.
+func TestSeekAfterPaddedRead(t *testing.T) {
+	f := bytes.NewReader(testString)
+	chunkTest, _ := Make(f)
+	chunkTest.Skip()
+	chunkFoo, _ := Make(f)
+
+	chunkIN1, _ := Make(chunkFoo)
+	data := make([]byte, chunkIN1.Size())
+	chunkIN1.Read(data)
+
+	if pos, err := chunkIN1.Seek(0, io.SeekCurrent); pos != 1 || err != nil {
+		t.Errorf("Seek(0, io.SeekCurrent) after padded read: pos %d, err %s", pos, err)
+	}
+}
+
 // Test if we can read chunks inside chunks
 func TestSubChunks(t *testing.T) {
 	f := bytes.NewReader(testString)
@@ -216,3 +267,434 @@ func TestTTY(t *testing.T) {
 		t.Error("Chunk is apparently a TTY!?")
 	}
 }
+
+var formTestString = []byte{
+	'F', 'O', 'R', 'M', 0, 0, 0, 24,
+	'A', 'I', 'F', 'F',
+	'C', 'O', 'M', 'M', 0, 0, 0, 2, 1, 2,
+	'S', 'S', 'N', 'D', 0, 0, 0, 1, 9, 0,
+}
+
+func TestGroupChunk(t *testing.T) {
+	f := bytes.NewReader(formTestString)
+
+	form, err := Make(f)
+	if err != nil {
+		t.Fatalf("Failed to create FORM chunk: %s", err)
+	}
+	if kind := form.Kind(); kind != "group" {
+		t.Errorf("FORM kind '%s' != 'group'", kind)
+	}
+	if formType := form.FormType(); formType != "AIFF" {
+		t.Errorf("FORM form type '%s' != 'AIFF'", formType)
+	}
+	if size := form.Size(); size != 24 {
+		t.Errorf("FORM size %d != 24", size)
+	}
+
+	next := form.Iter()
+
+	comm, err := next()
+	if err != nil {
+		t.Fatalf("Failed to read COMM via Iter: %s", err)
+	}
+	if name := comm.Name(); name != "COMM" {
+		t.Errorf("First child name '%s' != 'COMM'", name)
+	}
+	if kind := comm.Kind(); kind != "data" {
+		t.Errorf("COMM kind '%s' != 'data'", kind)
+	}
+
+	ssnd, err := next()
+	if err != nil {
+		t.Fatalf("Failed to read SSND via Iter: %s", err)
+	}
+	if name := ssnd.Name(); name != "SSND" {
+		t.Errorf("Second child name '%s' != 'SSND'", name)
+	}
+	checkByte(ssnd, 9, t)
+
+	if _, err := next(); err != io.EOF {
+		t.Errorf("Expected io.EOF after last child, got %s", err)
+	}
+}
+
+var riffTestString = []byte{
+	'R', 'I', 'F', 'F', 12, 0, 0, 0,
+	'W', 'A', 'V', 'E',
+	'f', 'm', 't', ' ', 0, 0, 0, 0,
+}
+
+func TestByteOrder(t *testing.T) {
+	f := bytes.NewReader(riffTestString)
+
+	riff, err := NewWithOrder(f, binary.LittleEndian)
+	if err != nil {
+		t.Fatalf("Failed to create RIFF chunk: %s", err)
+	}
+	if kind := riff.Kind(); kind != "group" {
+		t.Errorf("RIFF kind '%s' != 'group'", kind)
+	}
+	if formType := riff.FormType(); formType != "WAVE" {
+		t.Errorf("RIFF form type '%s' != 'WAVE'", formType)
+	}
+	if size := riff.Size(); size != 12 {
+		t.Errorf("RIFF size %d != 12 (little-endian size misread?)", size)
+	}
+
+	next := riff.Iter()
+	fmtChunk, err := next()
+	if err != nil {
+		t.Fatalf("Failed to read fmt  via Iter: %s", err)
+	}
+	if name := fmtChunk.Name(); name != "fmt " {
+		t.Errorf("Child name '%s' != 'fmt '", name)
+	}
+
+	if _, err := next(); err != io.EOF {
+		t.Errorf("Expected io.EOF after last child, got %s", err)
+	}
+}
+
+func TestStreamChunkBasic(t *testing.T) {
+	f := bytes.NewReader(testString)
+
+	chunkTest, err := MakeStream(f)
+	if err != nil {
+		t.Fatalf("Failed to create StreamChunk: %s", err)
+	}
+	if name := chunkTest.Name(); name != "TEST" {
+		t.Errorf("Chunk name '%s' != 'TEST'", name)
+	}
+	if size := chunkTest.Size(); size != 2 {
+		t.Errorf("Chunk size %d != 2", size)
+	}
+
+	buffer := make([]byte, 10)
+	if n, err := chunkTest.Read(buffer); n != 2 || err != nil {
+		t.Errorf("Failed reading 2 bytes; got %d (%s)", n, err)
+	}
+	if buffer[0] != 42 || buffer[1] != 24 {
+		t.Error("Invalid data in output.")
+	}
+	if n, err := chunkTest.Read(buffer); n > 0 || err != io.EOF {
+		t.Errorf("Didn't get EOF when reading past end of buffer (read %d bytes: %s)", n, err)
+	}
+
+	// The next chunk header should be readable immediately.
+	chunkFoo, err := MakeStream(f)
+	if err != nil {
+		t.Fatalf("Failed to create next StreamChunk: %s", err)
+	}
+	if name := chunkFoo.Name(); name != "FOO " {
+		t.Errorf("Next chunk not called \"FOO \" (got \"%s\")", name)
+	}
+}
+
+func TestStreamChunkSkipAndPadding(t *testing.T) {
+	f := bytes.NewReader(testString)
+
+	chunkTest, _ := MakeStream(f)
+	if err := chunkTest.Skip(); err != nil {
+		t.Fatalf("Failed to skip TEST: %s", err)
+	}
+
+	chunkFoo, err := MakeStream(f)
+	if err != nil {
+		t.Fatalf("Failed to create FOO StreamChunk: %s", err)
+	}
+
+	// IN1  has an odd size (1), so reading it fully must also consume its
+	// pad byte, leaving IN2  immediately readable.
+	chunkIN1, err := MakeStream(chunkFoo)
+	if err != nil {
+		t.Fatalf("Failed to create IN1  StreamChunk: %s", err)
+	}
+	data := make([]byte, chunkIN1.Size())
+	if _, err := chunkIN1.Read(data); err != nil {
+		t.Errorf("Failed to read IN1 : %s", err)
+	}
+	if !bytes.Equal(data, []byte{255}) {
+		t.Error("IN1  didn't have expected content")
+	}
+
+	chunkIN2, err := MakeStream(chunkFoo)
+	if err != nil {
+		t.Fatalf("Failed to create IN2  StreamChunk: %s", err)
+	}
+	if name := chunkIN2.Name(); name != "IN2 " {
+		t.Errorf("Second chunk named '%s'; expected 'IN2 '", name)
+	}
+}
+
+func TestIndex(t *testing.T) {
+	f := bytes.NewReader(formTestString)
+
+	entries, err := Index(f)
+	if err != nil {
+		t.Fatalf("Failed to index: %s", err)
+	}
+
+	expected := []ChunkEntry{
+		{ID: "FORM", Offset: 0, Size: 24, Path: "FORM"},
+		{ID: "COMM", Offset: 12, Size: 2, Path: "FORM/COMM"},
+		{ID: "SSND", Offset: 22, Size: 1, Path: "FORM/SSND"},
+	}
+	if len(entries) != len(expected) {
+		t.Fatalf("Got %d entries, expected %d: %+v", len(entries), len(expected), entries)
+	}
+	for i, want := range expected {
+		if entries[i] != want {
+			t.Errorf("Entry %d = %+v, want %+v", i, entries[i], want)
+		}
+	}
+}
+
+func TestCatalogOpen(t *testing.T) {
+	f := bytes.NewReader(formTestString)
+
+	catalog, err := NewCatalog(f)
+	if err != nil {
+		t.Fatalf("Failed to build catalog: %s", err)
+	}
+
+	comm, err := catalog.Open("FORM/COMM")
+	if err != nil {
+		t.Fatalf("Failed to open FORM/COMM: %s", err)
+	}
+	if name := comm.Name(); name != "COMM" {
+		t.Errorf("Opened chunk name '%s' != 'COMM'", name)
+	}
+	checkByte(comm, 1, t)
+
+	ssnd, err := catalog.Open("FORM/SSND")
+	if err != nil {
+		t.Fatalf("Failed to open FORM/SSND: %s", err)
+	}
+	checkByte(ssnd, 9, t)
+
+	if _, err := catalog.Open("FORM/NOPE"); err == nil {
+		t.Error("Expected error opening a path not in the catalog")
+	}
+}
+
+func TestDecodeAIFFCommon(t *testing.T) {
+	// COMM payload: 1 channel, 2 sample frames, 16-bit samples, 44100Hz
+	// (as an 80-bit extended float).
+	data := []byte{
+		'C', 'O', 'M', 'M', 0, 0, 0, 18,
+		0, 1, // NumChannels
+		0, 0, 0, 2, // NumSampleFrames
+		0, 16, // SampleSize
+		0x40, 0x0E, 0xAC, 0x44, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, // 44100.0
+	}
+	f := bytes.NewReader(data)
+	chunk, err := Make(f)
+	if err != nil {
+		t.Fatalf("Failed to create COMM chunk: %s", err)
+	}
+
+	decoded, err := chunk.Decode()
+	if err != nil {
+		t.Fatalf("Failed to decode COMM: %s", err)
+	}
+	comm, ok := decoded.(*AIFFCommon)
+	if !ok {
+		t.Fatalf("Decode returned %T, not *AIFFCommon", decoded)
+	}
+	if comm.NumChannels != 1 || comm.NumSampleFrames != 2 || comm.SampleSize != 16 {
+		t.Errorf("Unexpected COMM fields: %+v", comm)
+	}
+	if comm.SampleRate != 44100.0 {
+		t.Errorf("SampleRate %v != 44100.0", comm.SampleRate)
+	}
+}
+
+func TestDecodeWAVFormat(t *testing.T) {
+	// fmt  payload: PCM, 2 channels, 44100Hz, 16-bit.
+	data := []byte{
+		'f', 'm', 't', ' ', 16, 0, 0, 0,
+		1, 0, // AudioFormat = PCM
+		2, 0, // NumChannels
+		0x44, 0xAC, 0x00, 0x00, // SampleRate = 44100
+		0x10, 0xB1, 0x02, 0x00, // ByteRate
+		4, 0, // BlockAlign
+		16, 0, // BitsPerSample
+	}
+	f := bytes.NewReader(data)
+	chunk, err := NewWithOrder(f, binary.LittleEndian)
+	if err != nil {
+		t.Fatalf("Failed to create fmt  chunk: %s", err)
+	}
+
+	decoded, err := chunk.Decode()
+	if err != nil {
+		t.Fatalf("Failed to decode fmt : %s", err)
+	}
+	format, ok := decoded.(*WAVFormat)
+	if !ok {
+		t.Fatalf("Decode returned %T, not *WAVFormat", decoded)
+	}
+	if format.AudioFormat != 1 || format.NumChannels != 2 || format.SampleRate != 44100 || format.BitsPerSample != 16 {
+		t.Errorf("Unexpected fmt  fields: %+v", format)
+	}
+}
+
+func TestDecodeILBMColorMap(t *testing.T) {
+	data := []byte{
+		'C', 'M', 'A', 'P', 0, 0, 0, 6,
+		255, 0, 0,
+		0, 255, 0,
+	}
+	f := bytes.NewReader(data)
+	chunk, _ := Make(f)
+
+	decoded, err := chunk.Decode()
+	if err != nil {
+		t.Fatalf("Failed to decode CMAP: %s", err)
+	}
+	cmap, ok := decoded.(*ILBMColorMap)
+	if !ok {
+		t.Fatalf("Decode returned %T, not *ILBMColorMap", decoded)
+	}
+	if len(cmap.Colors) != 2 || cmap.Colors[0] != (RGB{255, 0, 0}) || cmap.Colors[1] != (RGB{0, 255, 0}) {
+		t.Errorf("Unexpected CMAP colors: %+v", cmap.Colors)
+	}
+}
+
+func TestDecodeILBMColorMapMalformed(t *testing.T) {
+	data := []byte{
+		'C', 'M', 'A', 'P', 0, 0, 0, 4,
+		255, 0, 0, 0,
+	}
+	f := bytes.NewReader(data)
+	chunk, _ := Make(f)
+
+	if _, err := chunk.Decode(); err == nil {
+		t.Error("Expected an error decoding a CMAP whose size isn't a multiple of 3")
+	}
+}
+
+func TestDecodeUnregistered(t *testing.T) {
+	f := bytes.NewReader(testString)
+	chunk, _ := Make(f)
+
+	if _, err := chunk.Decode(); err == nil {
+		t.Error("Expected an error decoding a chunk with no registered Decoder")
+	}
+}
+
+func tempWriteSeeker(t *testing.T) *os.File {
+	f, err := os.CreateTemp("", "chunk_test")
+	if err != nil {
+		t.Fatalf("Failed to create temp file: %s", err)
+	}
+	t.Cleanup(func() {
+		f.Close()
+		os.Remove(f.Name())
+	})
+	return f
+}
+
+func TestWriterBasic(t *testing.T) {
+	f := tempWriteSeeker(t)
+
+	w, err := NewWriter(f, "TEST")
+	if err != nil {
+		t.Fatalf("Failed to create Writer: %s", err)
+	}
+	if _, err := w.Write([]byte{42, 24}); err != nil {
+		t.Errorf("Failed to write payload: %s", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Errorf("Failed to close Writer: %s", err)
+	}
+
+	f.Seek(0, io.SeekStart)
+	chunk, err := Make(f)
+	if err != nil {
+		t.Fatalf("Failed to read back written chunk: %s", err)
+	}
+	if name := chunk.Name(); name != "TEST" {
+		t.Errorf("Chunk name '%s' != 'TEST'", name)
+	}
+	if size := chunk.Size(); size != 2 {
+		t.Errorf("Chunk size %d != 2", size)
+	}
+	buffer := make([]byte, 2)
+	chunk.Read(buffer)
+	if buffer[0] != 42 || buffer[1] != 24 {
+		t.Error("Invalid data in round-tripped chunk")
+	}
+}
+
+// Writes the same structure as testString using Writer and SubWriter, and
+// checks that the bytes produced match it exactly, including padding.
+func TestWriterRoundTrip(t *testing.T) {
+	f := tempWriteSeeker(t)
+
+	top, err := NewWriter(f, "TEST")
+	if err != nil {
+		t.Fatalf("Failed to create top-level Writer: %s", err)
+	}
+	top.Write([]byte{42, 24})
+	if err := top.Close(); err != nil {
+		t.Errorf("Failed to close TEST: %s", err)
+	}
+
+	foo, err := NewWriter(f, "FOO ")
+	if err != nil {
+		t.Fatalf("Failed to create FOO Writer: %s", err)
+	}
+
+	in1, err := foo.SubWriter("IN1 ")
+	if err != nil {
+		t.Fatalf("Failed to create IN1 SubWriter: %s", err)
+	}
+	in1.Write([]byte{255})
+	if err := in1.Close(); err != nil {
+		t.Errorf("Failed to close IN1 : %s", err)
+	}
+
+	in2, err := foo.SubWriter("IN2 ")
+	if err != nil {
+		t.Fatalf("Failed to create IN2 SubWriter: %s", err)
+	}
+	in2.Write([]byte{127, 129})
+	if err := in2.Close(); err != nil {
+		t.Errorf("Failed to close IN2 : %s", err)
+	}
+
+	if err := foo.Close(); err != nil {
+		t.Errorf("Failed to close FOO : %s", err)
+	}
+	if foo.size != 20 {
+		t.Errorf("FOO  size %d != 20", foo.size)
+	}
+
+	written, err := os.ReadFile(f.Name())
+	if err != nil {
+		t.Fatalf("Failed to read back temp file: %s", err)
+	}
+	if !bytes.Equal(written, testString) {
+		t.Errorf("Written bytes %v != expected %v", written, testString)
+	}
+}
+
+func TestWriterClosedErrors(t *testing.T) {
+	f := tempWriteSeeker(t)
+
+	w, _ := NewWriter(f, "TEST")
+	w.Write([]byte{1, 2})
+	w.Close()
+
+	if _, err := w.Write([]byte{3}); err == nil {
+		t.Error("Expected error writing to closed Writer")
+	}
+	if _, err := w.SubWriter("SUB "); err == nil {
+		t.Error("Expected error creating SubWriter on closed Writer")
+	}
+	if err := w.Close(); err == nil {
+		t.Error("Expected error closing an already-closed Writer")
+	}
+}
@@ -0,0 +1,28 @@
+package chunk
+
+import "fmt"
+
+// A Decoder turns a chunk's raw payload into a typed value. The chunk is
+// passed as it is, so the Decoder reads from the chunk's current offset,
+// which for a freshly created chunk is the start of its payload.
+type Decoder func(*Chunk) (interface{}, error)
+
+var decoders = map[string]Decoder{}
+
+// Register associates a Decoder with a chunk ID, such as "COMM" or "fmt ",
+// so that Chunk.Decode can dispatch to it. Registering a Decoder for an ID
+// that already has one replaces it.
+func Register(id string, d Decoder) {
+	decoders[id] = d
+}
+
+// Decode looks up the Decoder registered for this chunk's Name and uses it
+// to parse the chunk's payload into a typed value. It returns an error if
+// no Decoder is registered for this chunk's ID.
+func (this *Chunk) Decode() (interface{}, error) {
+	d, ok := decoders[this.id]
+	if !ok {
+		return nil, fmt.Errorf("chunk: no decoder registered for %q", this.id)
+	}
+	return d(this)
+}
@@ -0,0 +1,199 @@
+package chunk
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+)
+
+func init() {
+	Register("COMM", decodeAIFFCommon)
+	Register("SSND", decodeAIFFSoundData)
+	Register("fmt ", decodeWAVFormat)
+	Register("data", decodeWAVData)
+	Register("BMHD", decodeILBMHeader)
+	Register("CMAP", decodeILBMColorMap)
+	Register("BODY", decodeILBMBody)
+}
+
+// AIFFCommon is the decoded payload of an AIFF "COMM" chunk.
+type AIFFCommon struct {
+	NumChannels     int16
+	NumSampleFrames uint32
+	SampleSize      int16
+	SampleRate      float64
+}
+
+func decodeAIFFCommon(c *Chunk) (interface{}, error) {
+	buf := make([]byte, 18)
+	if _, err := io.ReadFull(c, buf); err != nil {
+		return nil, err
+	}
+	return &AIFFCommon{
+		NumChannels:     int16(binary.BigEndian.Uint16(buf[0:2])),
+		NumSampleFrames: binary.BigEndian.Uint32(buf[2:6]),
+		SampleSize:      int16(binary.BigEndian.Uint16(buf[6:8])),
+		SampleRate:      decodeExtended(buf[8:18]),
+	}, nil
+}
+
+// AIFFSoundData is the decoded payload of an AIFF "SSND" chunk.
+type AIFFSoundData struct {
+	Offset    uint32
+	BlockSize uint32
+	Data      []byte
+}
+
+func decodeAIFFSoundData(c *Chunk) (interface{}, error) {
+	header := make([]byte, 8)
+	if _, err := io.ReadFull(c, header); err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(c)
+	if err != nil {
+		return nil, err
+	}
+	return &AIFFSoundData{
+		Offset:    binary.BigEndian.Uint32(header[0:4]),
+		BlockSize: binary.BigEndian.Uint32(header[4:8]),
+		Data:      data,
+	}, nil
+}
+
+// decodeExtended converts a 10-byte big-endian IEEE 754 80-bit extended
+// precision float, as used by AIFF's COMM.SampleRate, to a float64.
+func decodeExtended(b []byte) float64 {
+	expon := int(b[0])<<8 | int(b[1])
+	sign := 1.0
+	if expon&0x8000 != 0 {
+		sign = -1.0
+	}
+	expon &= 0x7fff
+
+	himant := uint64(binary.BigEndian.Uint32(b[2:6]))
+	lomant := uint64(binary.BigEndian.Uint32(b[6:10]))
+	if expon == 0 && himant == 0 && lomant == 0 {
+		return 0
+	}
+	if expon == 0x7fff {
+		return sign * math.Inf(1)
+	}
+
+	mantissa := float64(himant)*4294967296.0 + float64(lomant)
+	return sign * math.Ldexp(mantissa, expon-16383-63)
+}
+
+// WAVFormat is the decoded payload of a WAV "fmt " chunk.
+type WAVFormat struct {
+	AudioFormat   uint16
+	NumChannels   uint16
+	SampleRate    uint32
+	ByteRate      uint32
+	BlockAlign    uint16
+	BitsPerSample uint16
+}
+
+func decodeWAVFormat(c *Chunk) (interface{}, error) {
+	buf := make([]byte, 16)
+	if _, err := io.ReadFull(c, buf); err != nil {
+		return nil, err
+	}
+	return &WAVFormat{
+		AudioFormat:   binary.LittleEndian.Uint16(buf[0:2]),
+		NumChannels:   binary.LittleEndian.Uint16(buf[2:4]),
+		SampleRate:    binary.LittleEndian.Uint32(buf[4:8]),
+		ByteRate:      binary.LittleEndian.Uint32(buf[8:12]),
+		BlockAlign:    binary.LittleEndian.Uint16(buf[12:14]),
+		BitsPerSample: binary.LittleEndian.Uint16(buf[14:16]),
+	}, nil
+}
+
+// WAVData is the decoded payload of a WAV "data" chunk: the raw samples.
+type WAVData struct {
+	Data []byte
+}
+
+func decodeWAVData(c *Chunk) (interface{}, error) {
+	data, err := io.ReadAll(c)
+	if err != nil {
+		return nil, err
+	}
+	return &WAVData{Data: data}, nil
+}
+
+// ILBMHeader is the decoded payload of an ILBM "BMHD" chunk.
+type ILBMHeader struct {
+	Width            uint16
+	Height           uint16
+	X                int16
+	Y                int16
+	NumPlanes        byte
+	Masking          byte
+	Compression      byte
+	TransparentColor uint16
+	XAspect          byte
+	YAspect          byte
+	PageWidth        int16
+	PageHeight       int16
+}
+
+func decodeILBMHeader(c *Chunk) (interface{}, error) {
+	buf := make([]byte, 20)
+	if _, err := io.ReadFull(c, buf); err != nil {
+		return nil, err
+	}
+	return &ILBMHeader{
+		Width:            binary.BigEndian.Uint16(buf[0:2]),
+		Height:           binary.BigEndian.Uint16(buf[2:4]),
+		X:                int16(binary.BigEndian.Uint16(buf[4:6])),
+		Y:                int16(binary.BigEndian.Uint16(buf[6:8])),
+		NumPlanes:        buf[8],
+		Masking:          buf[9],
+		Compression:      buf[10],
+		TransparentColor: binary.BigEndian.Uint16(buf[12:14]),
+		XAspect:          buf[14],
+		YAspect:          buf[15],
+		PageWidth:        int16(binary.BigEndian.Uint16(buf[16:18])),
+		PageHeight:       int16(binary.BigEndian.Uint16(buf[18:20])),
+	}, nil
+}
+
+// RGB is a single entry of an ILBM "CMAP" color map.
+type RGB struct {
+	R, G, B byte
+}
+
+// ILBMColorMap is the decoded payload of an ILBM "CMAP" chunk.
+type ILBMColorMap struct {
+	Colors []RGB
+}
+
+func decodeILBMColorMap(c *Chunk) (interface{}, error) {
+	data, err := io.ReadAll(c)
+	if err != nil {
+		return nil, err
+	}
+	if len(data)%3 != 0 {
+		return nil, fmt.Errorf("chunk: CMAP size %d is not a multiple of 3", len(data))
+	}
+	colors := make([]RGB, len(data)/3)
+	for i := range colors {
+		colors[i] = RGB{data[i*3], data[i*3+1], data[i*3+2]}
+	}
+	return &ILBMColorMap{Colors: colors}, nil
+}
+
+// ILBMBody is the decoded payload of an ILBM "BODY" chunk: its (possibly
+// run-length encoded) bitplane data, left for the caller to interpret.
+type ILBMBody struct {
+	Data []byte
+}
+
+func decodeILBMBody(c *Chunk) (interface{}, error) {
+	data, err := io.ReadAll(c)
+	if err != nil {
+		return nil, err
+	}
+	return &ILBMBody{Data: data}, nil
+}
@@ -0,0 +1,79 @@
+package chunk
+
+import (
+	"encoding/binary"
+	"io"
+)
+
+// A StreamChunk is like a Chunk, but can be read from a plain io.Reader, such as
+// an HTTP response body, a pipe, or stdin, instead of requiring an
+// io.Seeker. It does not implement io.Seeker: once past the current read
+// position there's no way back.
+type StreamChunk struct {
+	r      io.Reader
+	lr     *io.LimitedReader
+	id     string
+	size   uint32
+	padded bool
+}
+
+// MakeStream reads a chunk header from r and returns a StreamChunk that
+// enforces the chunk's size boundary as r is read. An instance of
+// StreamChunk is specifically allowed as the argument to MakeStream; this is
+// used to read chunks inside other chunks.
+func MakeStream(r io.Reader) (*StreamChunk, error) {
+	id := make([]byte, 4)
+	if _, err := io.ReadFull(r, id); err != nil {
+		return nil, err
+	}
+	sizeBits := make([]byte, 4)
+	if _, err := io.ReadFull(r, sizeBits); err != nil {
+		return nil, err
+	}
+	size := binary.BigEndian.Uint32(sizeBits)
+	return &StreamChunk{r: r, lr: &io.LimitedReader{R: r, N: int64(size)}, id: string(id), size: size}, nil
+}
+
+// Returns the name (ID) of the chunk. This is the first 4 bytes of the chunk.
+func (this *StreamChunk) Name() (id string) {
+	return this.id
+}
+
+// Returns the size of the chunk.
+func (this *StreamChunk) Size() (size uint32) {
+	return this.size
+}
+
+// Read implements the io.Reader interface, returning io.EOF once Size()
+// bytes have been read. When the last byte of an odd-sized chunk is read,
+// the pad byte is consumed from the underlying reader so the next chunk
+// header can be read immediately.
+func (this *StreamChunk) Read(buffer []byte) (n int, err error) {
+	n, err = this.lr.Read(buffer)
+	if this.lr.N == 0 && this.size&1 == 1 && !this.padded {
+		this.padded = true
+		if _, padErr := io.CopyN(io.Discard, this.r, 1); padErr != nil && err == nil {
+			err = padErr
+		}
+	}
+	return
+}
+
+// Skip discards the remainder of the chunk, including its pad byte if any,
+// so that the underlying reader is positioned at the start of the next
+// chunk. Unlike Chunk.Skip, Skip can fail, since there's no way to recover
+// the position of an io.Reader once bytes have been discarded from it.
+func (this *StreamChunk) Skip() error {
+	if this.lr.N > 0 {
+		if _, err := io.CopyN(io.Discard, this.lr, this.lr.N); err != nil {
+			return err
+		}
+	}
+	if this.size&1 == 1 && !this.padded {
+		this.padded = true
+		if _, err := io.CopyN(io.Discard, this.r, 1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package chunk
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+// A Writer lets you produce a single chunk of an IFF file. It is the
+// write-side counterpart to Chunk: construct one with NewWriter, stream the
+// payload through Write, and call Close to back-patch the size header.
+type Writer struct {
+	w      io.WriteSeeker
+	id     string
+	size   uint32
+	offset int64
+	order  binary.ByteOrder
+	parent *Writer
+	closed bool
+}
+
+// NewWriter begins a new chunk in w, writing the 4-byte id immediately and
+// reserving space for the size field, which is filled in by Close. id must
+// be exactly 4 bytes long. The size field is written big-endian; use
+// NewWriterWithOrder to produce RIFF/RIFX (and therefore WAV/AVI) files,
+// whose size fields are little-endian. A Writer does not implement
+// io.Seeker, so it cannot itself be passed to NewWriter; use SubWriter to
+// write chunks inside other chunks.
+func NewWriter(w io.WriteSeeker, id string) (*Writer, error) {
+	return NewWriterWithOrder(w, id, binary.BigEndian)
+}
+
+// NewWriterWithOrder is like NewWriter, but writes the chunk's size field
+// using the given byte order.
+func NewWriterWithOrder(w io.WriteSeeker, id string, order binary.ByteOrder) (*Writer, error) {
+	if len(id) != 4 {
+		return nil, errors.New("Chunk id must be exactly 4 bytes")
+	}
+	if _, err := w.Write([]byte(id)); err != nil {
+		return nil, err
+	}
+	offset, err := w.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write([]byte{0, 0, 0, 0}); err != nil {
+		return nil, err
+	}
+	return &Writer{w: w, id: id, offset: offset, order: order}, nil
+}
+
+// Write implements the io.Writer interface, streaming p into the chunk's
+// payload. The written byte count is added to this chunk's size, and to the
+// size of any ancestor chunk created via SubWriter.
+func (this *Writer) Write(p []byte) (n int, err error) {
+	if this.closed {
+		return 0, errors.New("Writer is already closed")
+	}
+	n, err = this.w.Write(p)
+	this.addSize(n)
+	return
+}
+
+func (this *Writer) addSize(n int) {
+	this.size += uint32(n)
+	if this.parent != nil {
+		this.parent.addSize(n)
+	}
+}
+
+// SubWriter begins a new chunk nested inside this one, sharing the same
+// underlying io.WriteSeeker and byte order. The header written by the
+// sub-chunk counts towards this chunk's size, just as if it had been
+// passed to Write.
+func (this *Writer) SubWriter(id string) (*Writer, error) {
+	if this.closed {
+		return nil, errors.New("Writer is already closed")
+	}
+	child, err := NewWriterWithOrder(this.w, id, this.order)
+	if err != nil {
+		return nil, err
+	}
+	child.parent = this
+	this.addSize(8)
+	return child, nil
+}
+
+// Close back-patches the chunk's size header and, if the chunk's size is
+// odd, emits the pad byte required by the IFF format. Close must be called
+// after the last byte of the payload has been written, and before the
+// parent chunk (if any) is closed.
+func (this *Writer) Close() error {
+	if this.closed {
+		return errors.New("Writer is already closed")
+	}
+	if this.size&1 == 1 {
+		if _, err := this.w.Write([]byte{0}); err != nil {
+			return err
+		}
+		if this.parent != nil {
+			this.parent.addSize(1)
+		}
+	}
+	here, err := this.w.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	if _, err := this.w.Seek(this.offset, io.SeekStart); err != nil {
+		return err
+	}
+	sizeBits := make([]byte, 4)
+	this.order.PutUint32(sizeBits, this.size)
+	if _, err := this.w.Write(sizeBits); err != nil {
+		return err
+	}
+	if _, err := this.w.Seek(here, io.SeekStart); err != nil {
+		return err
+	}
+	this.closed = true
+	return nil
+}